@@ -3,10 +3,14 @@ package backfill
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand"
 	"net/http"
+	"net/url"
+	"runtime/debug"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +18,7 @@ import (
 	"github.com/bluesky-social/indigo/api/atproto"
 	"github.com/bluesky-social/indigo/repo"
 	"github.com/bluesky-social/indigo/repomgr"
+	lru "github.com/hashicorp/golang-lru/v2/expirable"
 	"github.com/ipfs/go-cid"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
@@ -21,6 +26,35 @@ import (
 	"golang.org/x/time/rate"
 )
 
+// FailureReason categorizes why a backfill job failed, so that retry policy,
+// alerting, and metrics can be driven off of something more structured than
+// a free-form "failed (...)" state string.
+type FailureReason string
+
+const (
+	// FailRepoNotFound means the PDS returned a 400 for getRepo; the repo
+	// doesn't exist (or the DID is wrong) and retrying won't help.
+	FailRepoNotFound FailureReason = "repo_not_found"
+	// FailUpstream5xx means the PDS returned a 5xx error.
+	FailUpstream5xx FailureReason = "upstream_5xx"
+	// FailRateLimited means the PDS returned a 429 or 503; its retries back
+	// off twice as long as those for other failures.
+	FailRateLimited FailureReason = "rate_limited"
+	// FailCARDecode means the response body couldn't be parsed as a repo CAR.
+	FailCARDecode FailureReason = "car_decode"
+	// FailTimeout means the request was cancelled by its context deadline.
+	FailTimeout FailureReason = "timeout"
+	// FailHandler means one of HandleCreateRecord/HandleUpdateRecord/
+	// HandleDeleteRecord returned an error for a record. This doesn't fail
+	// the job outright - most records in a large repo succeeding despite one
+	// bad record is still useful - but is recorded via SetFailure so it's
+	// visible through Job.Failure instead of only a log line. The attempt
+	// still counts as a success in backfillJobsProcessed (empty reason).
+	FailHandler FailureReason = "handler"
+	// FailUnknown is used when a failure doesn't fit any of the above.
+	FailUnknown FailureReason = "unknown"
+)
+
 // Job is an interface for a backfill job
 type Job interface {
 	Repo() string
@@ -30,6 +64,12 @@ type Job interface {
 	SetRev(ctx context.Context, rev string) error
 	RetryCount() int
 
+	// SetFailure records why the most recent backfill attempt failed.
+	SetFailure(ctx context.Context, reason FailureReason, detail string) error
+	// Failure returns the reason and detail recorded by the most recent
+	// SetFailure call.
+	Failure() (FailureReason, string)
+
 	BufferOps(ctx context.Context, since *string, rev string, ops []*bufferedOp) (bool, error)
 	// FlushBufferedOps calls the given callback for each buffered operation
 	// Once done it clears the buffer and marks the job as "complete"
@@ -45,10 +85,23 @@ type Store interface {
 	// BufferOp buffers an operation for a job and returns true if the operation was buffered
 	// If the operation was not buffered, it returns false and an error (ErrJobNotFound or ErrJobComplete)
 	GetJob(ctx context.Context, repo string) (Job, error)
+	// GetNextEnqueuedJob returns the next enqueued job to backfill, if any.
+	// Jobs that are waiting out a retry backoff (see BumpRetry) must not be
+	// returned until their retryAt has passed.
 	GetNextEnqueuedJob(ctx context.Context) (Job, error)
 	UpdateRev(ctx context.Context, repo, rev string) error
 
 	EnqueueJob(ctx context.Context, repo string) error
+
+	// BumpRetry increments the retry count for repo's job and records
+	// retryAt as the time at which it may next be picked up by
+	// GetNextEnqueuedJob. The caller (see nextRetryAt) computes retryAt
+	// using an exponential backoff with jitter so that jobs which fail
+	// together (e.g. from a shared upstream outage) don't all retry in
+	// lockstep against the PDS. reason is recorded alongside the bump so
+	// per-reason retry behavior (e.g. FailRateLimited never retrying sooner
+	// than a longer floor) can be layered on by the Store if desired.
+	BumpRetry(ctx context.Context, repo string, reason FailureReason, retryAt time.Time) (nextAttempt int, err error)
 }
 
 // Backfiller is a struct which handles backfilling a repo
@@ -68,12 +121,22 @@ type Backfiller struct {
 	NSIDFilter   string
 	CheckoutPath string
 
+	// PDSResolver resolves the PDS currently hosting a repo, so that
+	// BackfillRepo can fetch from the right host instead of assuming
+	// everyone is on CheckoutPath's host. Defaults to a resolver backed by
+	// the PLC directory and did:web well-known documents.
+	PDSResolver PDSResolver
+
 	syncLimiter *rate.Limiter
 
 	magicHeaderKey string
 	magicHeaderVal string
 
 	stop chan chan struct{}
+
+	// subscribers holds the channels returned by Subscribe, keyed by an
+	// opaque per-subscription token.
+	subscribers sync.Map
 }
 
 var (
@@ -140,10 +203,31 @@ func NewBackfiller(
 		NSIDFilter:            opts.NSIDFilter,
 		syncLimiter:           rate.NewLimiter(rate.Limit(opts.SyncRequestsPerSecond), 1),
 		CheckoutPath:          opts.CheckoutPath,
+		PDSResolver:           newPLCResolver(opts.CheckoutPath),
 		stop:                  make(chan chan struct{}, 1),
 	}
 }
 
+// runSafely runs fn, recovering from and logging any panic instead of
+// letting it crash the whole backfill processor. repoDID may be empty for
+// helpers that aren't tied to a specific repo. Callers whose goroutine holds a sync.WaitGroup slot must
+// release it themselves, even on panic - runSafely only recovers, it
+// doesn't know about the caller's bookkeeping.
+func runSafely(log *slog.Logger, label, repoDID string, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			backfillPanics.WithLabelValues(label).Inc()
+			log.Error("recovered from panic in backfill goroutine",
+				"label", label,
+				"repo", repoDID,
+				"panic", r,
+				"stack", string(debug.Stack()),
+			)
+		}
+	}()
+	fn()
+}
+
 // Start starts the backfill processor routine
 func (b *Backfiller) Start() {
 	ctx := context.Background()
@@ -177,34 +261,125 @@ func (b *Backfiller) Start() {
 		log := log.With("repo", job.Repo())
 
 		// Mark the backfill as "in progress"
+		oldState := job.State()
 		err = job.SetState(ctx, StateInProgress)
 		if err != nil {
 			log.Error("failed to set job state", "error", err)
 			continue
 		}
+		b.emit(BackfillEvent{Repo: job.Repo(), OldState: oldState, NewState: StateInProgress, Rev: job.Rev()})
 
 		sem.Acquire(ctx, 1)
 		go func(j Job) {
 			defer sem.Release(1)
-			newState, err := b.BackfillRepo(ctx, j)
-			if err != nil {
-				log.Error("failed to backfill repo", "error", err)
+			runSafely(log, "backfill_repo", j.Repo(), func() {
+				b.startBackfillRepo(ctx, log, j)
+			})
+		}(job)
+	}
+}
+
+// startBackfillRepo runs BackfillRepo for j and applies the resulting state
+// transition. Split out from Start so it can be wrapped in runSafely.
+func (b *Backfiller) startBackfillRepo(ctx context.Context, log *slog.Logger, j Job) {
+	newState, err := b.safeBackfillRepo(ctx, log, j)
+	if err != nil {
+		log.Error("failed to backfill repo", "error", err)
+	}
+	b.applyBackfillResult(ctx, log, j, newState, err)
+}
+
+// safeBackfillRepo calls BackfillRepo, recovering from any panic it raises
+// on the calling goroutine; the producer/consumer goroutines it spawns
+// recover their own panics through runSafely. Without this, a panic
+// would unwind straight out of the runSafely wrapping Start's goroutine
+// before the job's state was ever updated, abandoning it in StateInProgress
+// forever - GetNextEnqueuedJob never returns it again, so it would never be
+// retried despite MaxRetries existing for exactly this purpose.
+func (b *Backfiller) safeBackfillRepo(ctx context.Context, log *slog.Logger, j Job) (string, error) {
+	return recoverBackfillAttempt(ctx, log, j, func() (string, error) {
+		return b.BackfillRepo(ctx, j)
+	})
+}
+
+// recoverBackfillAttempt runs attempt, recovering from any panic and turning
+// it into an ordinary failed state/error pair so the caller's normal
+// retry-or-fail handling (see applyBackfillResult) applies to it exactly
+// like any other failure. Split out from safeBackfillRepo so the recovery
+// behavior can be unit tested without going through a real (networked)
+// BackfillRepo call.
+func recoverBackfillAttempt(ctx context.Context, log *slog.Logger, j Job, attempt func() (string, error)) (newState string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			backfillPanics.WithLabelValues("backfill_repo").Inc()
+			log.Error("recovered from panic backfilling repo",
+				"panic", r,
+				"stack", string(debug.Stack()),
+			)
+			newState = failState(ctx, j, log, FailUnknown, "panic")
+			err = fmt.Errorf("panic backfilling repo: %v", r)
+		}
+	}()
+	return attempt()
+}
+
+// applyBackfillResult applies the state transition resulting from a
+// BackfillRepo attempt: enqueues a retry with backoff if the failure is
+// retryable, marks the job failed_permanent and clears its buffered ops
+// otherwise, and always records the outcome in backfillJobsProcessed. Split
+// out from startBackfillRepo so this branching can be unit tested against a
+// fake Job/Store without going through an actual (networked) BackfillRepo
+// call.
+func (b *Backfiller) applyBackfillResult(ctx context.Context, log *slog.Logger, j Job, newState string, err error) {
+	reason := FailureReason("")
+	if newState != "" {
+		failed := strings.HasPrefix(newState, "failed")
+		if failed {
+			reason, _ = j.Failure()
+		}
+
+		// FailRepoNotFound is never worth retrying: the repo isn't there.
+		retryable := failed && reason != FailRepoNotFound && j.RetryCount() < MaxRetries
+		if retryable {
+			backoffAttempt := j.RetryCount() + 1
+			if reason == FailRateLimited {
+				// One extra doubling of the backoff for rate-limited failures.
+				backoffAttempt++
+			}
+			retryAt := nextRetryAt(backoffAttempt)
+			attempt, berr := b.Store.BumpRetry(ctx, j.Repo(), reason, retryAt)
+			if berr != nil {
+				log.Error("failed to bump retry count", "error", berr)
+			}
+			log.Info("scheduling backfill retry", "attempt", attempt, "retry_at", retryAt, "reason", reason)
+			if sserr := j.SetState(ctx, StateEnqueued); sserr != nil {
+				log.Error("failed to set job state", "error", sserr)
+			}
+			b.emit(BackfillEvent{Repo: j.Repo(), OldState: StateInProgress, NewState: StateEnqueued, Rev: j.Rev(), Err: err})
+		} else {
+			if failed {
+				// Either retries are exhausted or the failure is terminal
+				// (e.g. FailRepoNotFound): this failure is final.
+				newState = strings.Replace(newState, "failed", "failed_permanent", 1)
+			}
+			if sserr := j.SetState(ctx, newState); sserr != nil {
+				log.Error("failed to set job state", "error", sserr)
 			}
-			if newState != "" {
-				if sserr := j.SetState(ctx, newState); sserr != nil {
-					log.Error("failed to set job state", "error", sserr)
-				}
 
-				if strings.HasPrefix(newState, "failed") {
-					// Clear buffered ops
-					if err := j.ClearBufferedOps(ctx); err != nil {
-						log.Error("failed to clear buffered ops", "error", err)
-					}
+			if failed {
+				// Clear buffered ops
+				if err := j.ClearBufferedOps(ctx); err != nil {
+					log.Error("failed to clear buffered ops", "error", err)
 				}
+				b.emit(BackfillEvent{Repo: j.Repo(), OldState: StateInProgress, NewState: newState, Rev: j.Rev(), Err: err})
 			}
-			backfillJobsProcessed.WithLabelValues(b.Name).Inc()
-		}(job)
+			// No event is emitted here on success: FlushBuffer already
+			// emitted the transition it made (StateComplete, or
+			// StateEnqueued after an ErrEventGap). SetState here writes
+			// newState over whatever state FlushBuffer set.
+		}
 	}
+	backfillJobsProcessed.WithLabelValues(b.Name, string(reason)).Inc()
 }
 
 // Stop stops the backfill processor
@@ -222,8 +397,84 @@ func (b *Backfiller) Stop(ctx context.Context) error {
 	}
 }
 
-// FlushBuffer processes buffered operations for a job
-func (b *Backfiller) FlushBuffer(ctx context.Context, job Job) int {
+// BackfillEvent describes a single state transition of a repo's backfill
+// job, emitted by Start and FlushBuffer on every state transition.
+type BackfillEvent struct {
+	Repo     string
+	OldState string
+	NewState string
+	Rev      string
+
+	// RecordsBackfilled is the number of records read from the initial CAR
+	// checkout, set on the transition into StateComplete.
+	RecordsBackfilled int
+	// BufferedReplayed is the number of buffered live ops replayed while
+	// flushing the buffer, set on transitions emitted from FlushBuffer.
+	BufferedReplayed int
+
+	// Err is set if the transition was the result of a failure.
+	Err error
+}
+
+// subscriberBufferSize bounds how many events a subscriber can fall behind
+// by before further sends are dropped rather than blocking the state
+// machine.
+const subscriberBufferSize = 64
+
+// subscriber wraps a Subscribe channel with the lock needed to serialize its
+// close (once ctx is done) against concurrent sends from emit - without it,
+// emit's "select { case ch <- evt: }" can race a bare close(ch) on the same
+// channel and panic with "send on closed channel".
+type subscriber struct {
+	mu     sync.Mutex
+	ch     chan BackfillEvent
+	closed bool
+}
+
+// Subscribe returns a channel of BackfillEvent emitted on every backfill job
+// state transition. The channel is closed once ctx is done. If a subscriber
+// falls behind, further sends to it are dropped (and counted via
+// backfillSubscriberDrops) rather than blocking the backfiller.
+func (b *Backfiller) Subscribe(ctx context.Context) <-chan BackfillEvent {
+	sub := &subscriber{ch: make(chan BackfillEvent, subscriberBufferSize)}
+	key := new(struct{})
+	b.subscribers.Store(key, sub)
+
+	go func() {
+		<-ctx.Done()
+		b.subscribers.Delete(key)
+		sub.mu.Lock()
+		sub.closed = true
+		close(sub.ch)
+		sub.mu.Unlock()
+	}()
+
+	return sub.ch
+}
+
+// emit fans out evt to all current subscribers, dropping it for any
+// subscriber whose buffer is full instead of blocking.
+func (b *Backfiller) emit(evt BackfillEvent) {
+	b.subscribers.Range(func(key, value any) bool {
+		sub := value.(*subscriber)
+		sub.mu.Lock()
+		defer sub.mu.Unlock()
+		if sub.closed {
+			return true
+		}
+		select {
+		case sub.ch <- evt:
+		default:
+			backfillSubscriberDrops.WithLabelValues(b.Name).Inc()
+		}
+		return true
+	})
+}
+
+// FlushBuffer processes buffered operations for a job. recordsBackfilled is
+// only used to populate the RecordsBackfilled field of the BackfillEvent
+// emitted on completion; pass 0 if unknown.
+func (b *Backfiller) FlushBuffer(ctx context.Context, job Job, recordsBackfilled int) int {
 	ctx, span := tracer.Start(ctx, "FlushBuffer")
 	defer span.End()
 	log := slog.With("source", "backfiller_buffer_flush", "repo", job.Repo())
@@ -234,44 +485,54 @@ func (b *Backfiller) FlushBuffer(ctx context.Context, job Job) int {
 
 	// Flush buffered operations, clear the buffer, and mark the job as "complete"
 	// Clearning and marking are handled by the job interface
-	err := job.FlushBufferedOps(ctx, func(kind, rev, path string, rec *[]byte, cid *cid.Cid) error {
+	flushErr := job.FlushBufferedOps(ctx, func(kind, rev, path string, rec *[]byte, cid *cid.Cid) error {
 		switch repomgr.EventKind(kind) {
 		case repomgr.EvtKindCreateRecord:
 			err := b.HandleCreateRecord(ctx, repo, rev, path, rec, cid)
 			if err != nil {
 				log.Error("failed to handle create record", "error", err)
+				if serr := job.SetFailure(ctx, FailHandler, err.Error()); serr != nil {
+					log.Error("failed to record handler failure reason", "error", serr)
+				}
 			}
 		case repomgr.EvtKindUpdateRecord:
 			err := b.HandleUpdateRecord(ctx, repo, rev, path, rec, cid)
 			if err != nil {
 				log.Error("failed to handle update record", "error", err)
+				if serr := job.SetFailure(ctx, FailHandler, err.Error()); serr != nil {
+					log.Error("failed to record handler failure reason", "error", serr)
+				}
 			}
 		case repomgr.EvtKindDeleteRecord:
 			err := b.HandleDeleteRecord(ctx, repo, rev, path)
 			if err != nil {
 				log.Error("failed to handle delete record", "error", err)
+				if serr := job.SetFailure(ctx, FailHandler, err.Error()); serr != nil {
+					log.Error("failed to record handler failure reason", "error", serr)
+				}
 			}
 		}
 		backfillOpsBuffered.WithLabelValues(b.Name).Dec()
 		processed++
 		return nil
 	})
-	if err != nil {
-		log.Error("failed to flush buffered ops", "error", err)
-		if errors.Is(err, ErrEventGap) {
+	if flushErr != nil {
+		log.Error("failed to flush buffered ops", "error", flushErr)
+		if errors.Is(flushErr, ErrEventGap) {
 			if sserr := job.SetState(ctx, StateEnqueued); sserr != nil {
 				log.Error("failed to reset job state after failed buffer flush", "error", sserr)
 			}
+			b.emit(BackfillEvent{Repo: repo, OldState: StateInProgress, NewState: StateEnqueued, Rev: job.Rev(), BufferedReplayed: processed, Err: flushErr})
 			// TODO: need to re-queue this job for later
 			return processed
 		}
 	}
 
 	// Mark the job as "complete"
-	err = job.SetState(ctx, StateComplete)
-	if err != nil {
-		log.Error("failed to set job state", "error", err)
+	if sserr := job.SetState(ctx, StateComplete); sserr != nil {
+		log.Error("failed to set job state", "error", sserr)
 	}
+	b.emit(BackfillEvent{Repo: repo, OldState: StateInProgress, NewState: StateComplete, Rev: job.Rev(), RecordsBackfilled: recordsBackfilled, BufferedReplayed: processed, Err: flushErr})
 
 	return processed
 }
@@ -286,6 +547,31 @@ type recordResult struct {
 	err        error
 }
 
+// failState records reason/detail on job via SetFailure and returns the
+// free-form state string the caller still uses to decide whether to
+// transition the job back to StateEnqueued or into failed_permanent.
+func failState(ctx context.Context, job Job, log *slog.Logger, reason FailureReason, detail string) string {
+	if err := job.SetFailure(ctx, reason, detail); err != nil {
+		log.Error("failed to record failure reason", "error", err, "reason", reason)
+	}
+	return fmt.Sprintf("failed (%s)", detail)
+}
+
+// classifyStatus maps a getRepo HTTP response status to a FailureReason and
+// human-readable detail string.
+func classifyStatus(status int, statusText string) (FailureReason, string) {
+	switch {
+	case status == http.StatusBadRequest:
+		return FailRepoNotFound, "repo not found"
+	case status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable:
+		return FailRateLimited, statusText
+	case status >= 500:
+		return FailUpstream5xx, statusText
+	default:
+		return FailUnknown, statusText
+	}
+}
+
 // BackfillRepo backfills a repo
 func (b *Backfiller) BackfillRepo(ctx context.Context, job Job) (string, error) {
 	ctx, span := tracer.Start(ctx, "BackfillRepo")
@@ -301,10 +587,31 @@ func (b *Backfiller) BackfillRepo(ctx context.Context, job Job) (string, error)
 	}
 	log.Info(fmt.Sprintf("processing backfill for %s", repoDid))
 
-	url := fmt.Sprintf("%s?did=%s", b.CheckoutPath, repoDid)
+	// PDSResolver is only set up by NewBackfiller; callers that build a
+	// Backfiller{} directly (as our own tests do) won't have one, so fall
+	// back to CheckoutPath rather than nil-pointer-panicking on it.
+	var pdsBase string
+	var err error
+	if b.PDSResolver != nil {
+		pdsBase, err = b.PDSResolver.ResolveRepoService(ctx, repoDid)
+		if err != nil {
+			log.Warn("failed to resolve PDS for repo, falling back to default checkout path", "error", err)
+		}
+	}
+	if pdsBase == "" {
+		pdsBase = b.CheckoutPath
+	}
 
-	if job.Rev() != "" {
-		url = url + fmt.Sprintf("&since=%s", job.Rev())
+	candidates := []string{pdsBase}
+	// Only worth asking for more candidates if the primary resolution
+	// actually succeeded - otherwise this would just repeat the same
+	// (just-failed) lookup against plc.directory/did:web a second time.
+	if err == nil {
+		if mr, ok := b.PDSResolver.(multiPDSResolver); ok {
+			if more, merr := mr.ResolveRepoServiceCandidates(ctx, repoDid); merr == nil && len(more) > 0 {
+				candidates = more
+			}
+		}
 	}
 
 	// GET and CAR decode the body
@@ -312,35 +619,56 @@ func (b *Backfiller) BackfillRepo(ctx context.Context, job Job) (string, error)
 		Transport: otelhttp.NewTransport(http.DefaultTransport),
 		Timeout:   600 * time.Second,
 	}
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		state := fmt.Sprintf("failed (create request: %s)", err.Error())
-		return state, fmt.Errorf("failed to create request: %w", err)
-	}
 
-	req.Header.Set("Accept", "application/vnd.ipld.car")
-	req.Header.Set("User-Agent", fmt.Sprintf("atproto-backfill-%s/0.0.1", b.Name))
-	if b.magicHeaderKey != "" && b.magicHeaderVal != "" {
-		req.Header.Set(b.magicHeaderKey, b.magicHeaderVal)
-	}
+	var resp *http.Response
+	for i, base := range candidates {
+		// CheckoutPath (the fallback) is a full getRepo endpoint URL, not a
+		// bare host, so strip that path before appending it again.
+		host := strings.TrimSuffix(strings.TrimSuffix(base, "/"), "/xrpc/com.atproto.sync.getRepo")
+		reqURL := fmt.Sprintf("%s/xrpc/com.atproto.sync.getRepo?did=%s", host, repoDid)
+		if job.Rev() != "" {
+			reqURL = reqURL + fmt.Sprintf("&since=%s", job.Rev())
+		}
 
-	b.syncLimiter.Wait(ctx)
+		req, rerr := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+		if rerr != nil {
+			detail := fmt.Sprintf("create request: %s", rerr.Error())
+			return failState(ctx, job, log, FailUnknown, detail), fmt.Errorf("failed to create request: %w", rerr)
+		}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		state := fmt.Sprintf("failed (do request: %s)", err.Error())
-		return state, fmt.Errorf("failed to send request: %w", err)
+		req.Header.Set("Accept", "application/vnd.ipld.car")
+		req.Header.Set("User-Agent", fmt.Sprintf("atproto-backfill-%s/0.0.1", b.Name))
+		if b.magicHeaderKey != "" && b.magicHeaderVal != "" {
+			req.Header.Set(b.magicHeaderKey, b.magicHeaderVal)
+		}
+
+		// Sequentially, not in parallel, so we don't blow through the rate
+		// limiter trying every candidate PDS at once.
+		b.syncLimiter.Wait(ctx)
+
+		candidateResp, derr := client.Do(req)
+		if derr != nil {
+			reason := FailUnknown
+			if errors.Is(derr, context.DeadlineExceeded) {
+				reason = FailTimeout
+			}
+			detail := fmt.Sprintf("do request: %s", derr.Error())
+			return failState(ctx, job, log, reason, detail), fmt.Errorf("failed to send request: %w", derr)
+		}
+
+		if candidateResp.StatusCode >= 400 && candidateResp.StatusCode < 500 && i < len(candidates)-1 {
+			log.Warn("PDS candidate rejected getRepo request, trying next candidate", "pds", base, "status", candidateResp.Status)
+			candidateResp.Body.Close()
+			continue
+		}
+
+		resp = candidateResp
+		break
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		reason := "unknown error"
-		if resp.StatusCode == http.StatusBadRequest {
-			reason = "repo not found"
-		} else {
-			reason = resp.Status
-		}
-		state := fmt.Sprintf("failed (%s)", reason)
-		return state, fmt.Errorf("failed to get repo: %s", reason)
+		reason, detail := classifyStatus(resp.StatusCode, resp.Status)
+		return failState(ctx, job, log, reason, detail), fmt.Errorf("failed to get repo: %s", detail)
 	}
 
 	instrumentedReader := instrumentedReader{
@@ -352,8 +680,8 @@ func (b *Backfiller) BackfillRepo(ctx context.Context, job Job) (string, error)
 
 	r, err := repo.ReadRepoFromCar(ctx, instrumentedReader)
 	if err != nil {
-		state := "failed (couldn't read repo CAR from response body)"
-		return state, fmt.Errorf("failed to read repo from car: %w", err)
+		detail := "couldn't read repo CAR from response body"
+		return failState(ctx, job, log, FailCARDecode, detail), fmt.Errorf("failed to read repo from car: %w", err)
 	}
 
 	numRecords := 0
@@ -362,7 +690,7 @@ func (b *Backfiller) BackfillRepo(ctx context.Context, job Job) (string, error)
 	recordResults := make(chan recordResult, numRoutines)
 
 	// Producer routine
-	go func() {
+	go runSafely(log, "backfill_producer", repoDid, func() {
 		defer close(recordQueue)
 		if err := r.ForEach(ctx, b.NSIDFilter, func(recordPath string, nodeCid cid.Cid) error {
 			numRecords++
@@ -371,7 +699,7 @@ func (b *Backfiller) BackfillRepo(ctx context.Context, job Job) (string, error)
 		}); err != nil {
 			log.Error("failed to iterate records in repo", "err", err)
 		}
-	}()
+	})
 
 	rev := r.SignedCommit().Rev
 
@@ -381,24 +709,29 @@ func (b *Backfiller) BackfillRepo(ctx context.Context, job Job) (string, error)
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for item := range recordQueue {
-				blk, err := r.Blockstore().Get(ctx, item.nodeCid)
-				if err != nil {
-					recordResults <- recordResult{recordPath: item.recordPath, err: fmt.Errorf("failed to get blocks for record: %w", err)}
-					continue
-				}
+			runSafely(log, "backfill_consumer", repoDid, func() {
+				for item := range recordQueue {
+					blk, err := r.Blockstore().Get(ctx, item.nodeCid)
+					if err != nil {
+						recordResults <- recordResult{recordPath: item.recordPath, err: fmt.Errorf("failed to get blocks for record: %w", err)}
+						continue
+					}
 
-				raw := blk.RawData()
+					raw := blk.RawData()
 
-				err = b.HandleCreateRecord(ctx, repoDid, rev, item.recordPath, &raw, &item.nodeCid)
-				if err != nil {
-					recordResults <- recordResult{recordPath: item.recordPath, err: fmt.Errorf("failed to handle create record: %w", err)}
-					continue
-				}
+					err = b.HandleCreateRecord(ctx, repoDid, rev, item.recordPath, &raw, &item.nodeCid)
+					if err != nil {
+						if serr := job.SetFailure(ctx, FailHandler, err.Error()); serr != nil {
+							log.Error("failed to record handler failure reason", "error", serr)
+						}
+						recordResults <- recordResult{recordPath: item.recordPath, err: fmt.Errorf("failed to handle create record: %w", err)}
+						continue
+					}
 
-				backfillRecordsProcessed.WithLabelValues(b.Name).Inc()
-				recordResults <- recordResult{recordPath: item.recordPath, err: err}
-			}
+					backfillRecordsProcessed.WithLabelValues(b.Name).Inc()
+					recordResults <- recordResult{recordPath: item.recordPath, err: err}
+				}
+			})
 		}()
 	}
 
@@ -407,11 +740,13 @@ func (b *Backfiller) BackfillRepo(ctx context.Context, job Job) (string, error)
 	// Handle results
 	go func() {
 		defer resultWG.Done()
-		for result := range recordResults {
-			if result.err != nil {
-				log.Error("Error processing record", "record", result.recordPath, "error", result.err)
+		runSafely(log, "backfill_result_drain", repoDid, func() {
+			for result := range recordResults {
+				if result.err != nil {
+					log.Error("Error processing record", "record", result.recordPath, "error", result.err)
+				}
 			}
-		}
+		})
 	}()
 
 	wg.Wait()
@@ -423,7 +758,7 @@ func (b *Backfiller) BackfillRepo(ctx context.Context, job Job) (string, error)
 	}
 
 	// Process buffered operations, marking the job as "complete" when done
-	numProcessed := b.FlushBuffer(ctx, job)
+	numProcessed := b.FlushBuffer(ctx, job, numRecords)
 
 	log.Info("backfill complete",
 		"buffered_records_processed", numProcessed,
@@ -559,3 +894,156 @@ var MaxRetries = 10
 func computeExponentialBackoff(attempt int) time.Duration {
 	return time.Duration(1<<uint(attempt)) * 10 * time.Second
 }
+
+// withJitter scales d by a random factor in [0.75, 1.25] so that many jobs
+// failing at once, e.g. from a shared upstream outage, don't all come back
+// for retry at exactly the same moment.
+func withJitter(d time.Duration) time.Duration {
+	jitter := 0.75 + rand.Float64()*0.5
+	return time.Duration(float64(d) * jitter)
+}
+
+// nextRetryAt computes the time at which a job should next be retried after
+// its attempt'th failure, using computeExponentialBackoff with jitter
+// applied.
+func nextRetryAt(attempt int) time.Time {
+	return time.Now().Add(withJitter(computeExponentialBackoff(attempt)))
+}
+
+// PDSResolver resolves the base URL of the PDS (Personal Data Server)
+// currently hosting a given repo DID. Implementations may be backed by the
+// PLC directory, a did:web document, a local cache of known users, etc.
+type PDSResolver interface {
+	ResolveRepoService(ctx context.Context, did string) (string, error)
+}
+
+// multiPDSResolver is an optional capability a PDSResolver may implement to
+// offer every candidate endpoint, primary first, e.g. when a DID document
+// lists more than one PDS service entry. When it returns any, BackfillRepo
+// uses that list in place of the primary returned by ResolveRepoService and
+// tries the entries sequentially, moving on when one is rejected with a 4xx.
+type multiPDSResolver interface {
+	ResolveRepoServiceCandidates(ctx context.Context, did string) ([]string, error)
+}
+
+// didDocument is the subset of a W3C DID document that we care about when
+// looking for a repo's PDS endpoint.
+type didDocument struct {
+	ID      string            `json:"id"`
+	Service []didServiceEntry `json:"service"`
+}
+
+type didServiceEntry struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
+const (
+	plcDirectoryURL      = "https://plc.directory"
+	pdsResolverCacheSize = 10_000
+	pdsResolverCacheTTL  = time.Hour
+)
+
+// plcResolver is the default PDSResolver. It resolves did:plc identifiers
+// against the PLC directory and did:web identifiers against their
+// well-known DID document, caching results since a repo's PDS rarely
+// changes and re-resolving on every backfill would be wasteful. Resolution
+// failures fall back to fallback (normally Backfiller.CheckoutPath).
+type plcResolver struct {
+	client   *http.Client
+	cache    *lru.LRU[string, []string]
+	fallback string
+}
+
+func newPLCResolver(fallback string) *plcResolver {
+	return &plcResolver{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		cache:    lru.NewLRU[string, []string](pdsResolverCacheSize, nil, pdsResolverCacheTTL),
+		fallback: fallback,
+	}
+}
+
+func (r *plcResolver) ResolveRepoService(ctx context.Context, did string) (string, error) {
+	candidates, err := r.ResolveRepoServiceCandidates(ctx, did)
+	if err != nil || len(candidates) == 0 {
+		return r.fallback, err
+	}
+	return candidates[0], nil
+}
+
+func (r *plcResolver) ResolveRepoServiceCandidates(ctx context.Context, did string) ([]string, error) {
+	if cached, ok := r.cache.Get(did); ok {
+		return cached, nil
+	}
+
+	doc, err := r.fetchDIDDocument(ctx, did)
+	if err != nil {
+		return []string{r.fallback}, fmt.Errorf("failed to fetch DID document: %w", err)
+	}
+
+	var candidates []string
+	for _, svc := range doc.Service {
+		if strings.HasSuffix(svc.ID, "#atproto_pds") || svc.Type == "AtprotoPersonalDataServer" {
+			candidates = append(candidates, strings.TrimSuffix(svc.ServiceEndpoint, "/"))
+		}
+	}
+	if len(candidates) == 0 {
+		return []string{r.fallback}, fmt.Errorf("no atproto_pds service found in DID document for %s", did)
+	}
+
+	r.cache.Add(did, candidates)
+	return candidates, nil
+}
+
+// didDocumentURL returns the URL to fetch did's DID document from: the PLC
+// directory for did:plc, or the well-known (possibly sub-path) document for
+// did:web.
+func didDocumentURL(did string) (string, error) {
+	switch {
+	case strings.HasPrefix(did, "did:plc:"):
+		return fmt.Sprintf("%s/%s", plcDirectoryURL, did), nil
+	case strings.HasPrefix(did, "did:web:"):
+		id := strings.TrimPrefix(did, "did:web:")
+		parts := strings.Split(id, ":")
+		host := parts[0]
+		if unescaped, err := url.PathUnescape(host); err == nil {
+			host = unescaped
+		}
+		if len(parts) == 1 {
+			return fmt.Sprintf("https://%s/.well-known/did.json", host), nil
+		}
+		return fmt.Sprintf("https://%s/%s/did.json", host, strings.Join(parts[1:], "/")), nil
+	default:
+		return "", fmt.Errorf("unsupported DID method for %q", did)
+	}
+}
+
+func (r *plcResolver) fetchDIDDocument(ctx context.Context, did string) (*didDocument, error) {
+	docURL, err := didDocumentURL(did)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", docURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", docURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status fetching %s: %s", docURL, resp.Status)
+	}
+
+	var doc didDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode DID document: %w", err)
+	}
+
+	return &doc, nil
+}
@@ -0,0 +1,43 @@
+package backfill
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeExponentialBackoff(t *testing.T) {
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 10 * time.Second},
+		{1, 20 * time.Second},
+		{2, 40 * time.Second},
+		{3, 80 * time.Second},
+	}
+	for _, c := range cases {
+		if got := computeExponentialBackoff(c.attempt); got != c.want {
+			t.Errorf("computeExponentialBackoff(%d) = %s, want %s", c.attempt, got, c.want)
+		}
+	}
+}
+
+func TestWithJitter(t *testing.T) {
+	base := 100 * time.Second
+	lo := time.Duration(float64(base) * 0.75)
+	hi := time.Duration(float64(base) * 1.25)
+	for i := 0; i < 100; i++ {
+		got := withJitter(base)
+		if got < lo || got > hi {
+			t.Fatalf("withJitter(%s) = %s, want within [%s, %s]", base, got, lo, hi)
+		}
+	}
+}
+
+func TestNextRetryAt(t *testing.T) {
+	before := time.Now()
+	retryAt := nextRetryAt(1)
+	if !retryAt.After(before) {
+		t.Fatalf("nextRetryAt(1) = %s, want after %s", retryAt, before)
+	}
+}
@@ -0,0 +1,26 @@
+package backfill
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestClassifyStatus(t *testing.T) {
+	cases := []struct {
+		status int
+		want   FailureReason
+	}{
+		{http.StatusBadRequest, FailRepoNotFound},
+		{http.StatusTooManyRequests, FailRateLimited},
+		{http.StatusServiceUnavailable, FailRateLimited},
+		{http.StatusInternalServerError, FailUpstream5xx},
+		{http.StatusBadGateway, FailUpstream5xx},
+		{http.StatusNotFound, FailUnknown},
+		{http.StatusForbidden, FailUnknown},
+	}
+	for _, c := range cases {
+		if got, _ := classifyStatus(c.status, "status text"); got != c.want {
+			t.Errorf("classifyStatus(%d, ...) = %q, want %q", c.status, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package backfill
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// backfillPanics counts panics recovered from by runSafely and
+// recoverBackfillAttempt, labeled by the
+// goroutine that panicked (e.g. "backfill_repo", "backfill_consumer").
+var backfillPanics = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "backfill_panics",
+	Help: "Number of panics recovered from in backfill goroutines",
+}, []string{"label"})
+
+// backfillSubscriberDrops counts BackfillEvent sends dropped by emit because
+// a Subscribe caller's channel was full.
+var backfillSubscriberDrops = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "backfill_subscriber_drops",
+	Help: "Number of BackfillEvents dropped because a subscriber's channel was full",
+}, []string{"name"})
+
+// backfillJobsProcessed counts completed backfill attempts, labeled by the
+// Backfiller's name and the FailureReason recorded for the attempt (empty
+// string for a successful attempt).
+var backfillJobsProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "backfill_jobs_processed",
+	Help: "Number of backfill jobs processed, labeled by outcome reason",
+}, []string{"name", "reason"})
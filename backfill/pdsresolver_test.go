@@ -0,0 +1,33 @@
+package backfill
+
+import "testing"
+
+func TestDIDDocumentURL(t *testing.T) {
+	cases := []struct {
+		did     string
+		want    string
+		wantErr bool
+	}{
+		{"did:plc:abc123", "https://plc.directory/did:plc:abc123", false},
+		{"did:web:example.com", "https://example.com/.well-known/did.json", false},
+		{"did:web:example.com:user:alice", "https://example.com/user/alice/did.json", false},
+		{"did:web:example.com%3A8080", "https://example.com:8080/.well-known/did.json", false},
+		{"did:key:z6Mk...", "", true},
+	}
+	for _, c := range cases {
+		got, err := didDocumentURL(c.did)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("didDocumentURL(%q) = %q, want error", c.did, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("didDocumentURL(%q) returned unexpected error: %v", c.did, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("didDocumentURL(%q) = %q, want %q", c.did, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// fakeJob is a minimal in-memory Job used to exercise applyBackfillResult's
+// state transition logic without a real Store.
+type fakeJob struct {
+	repo       string
+	state      string
+	rev        string
+	retryCount int
+
+	failReason FailureReason
+	failDetail string
+
+	clearedOps bool
+}
+
+func (j *fakeJob) Repo() string  { return j.repo }
+func (j *fakeJob) State() string { return j.state }
+func (j *fakeJob) Rev() string   { return j.rev }
+
+func (j *fakeJob) SetState(ctx context.Context, state string) error {
+	j.state = state
+	return nil
+}
+
+func (j *fakeJob) SetRev(ctx context.Context, rev string) error {
+	j.rev = rev
+	return nil
+}
+
+func (j *fakeJob) RetryCount() int { return j.retryCount }
+
+func (j *fakeJob) SetFailure(ctx context.Context, reason FailureReason, detail string) error {
+	j.failReason = reason
+	j.failDetail = detail
+	return nil
+}
+
+func (j *fakeJob) Failure() (FailureReason, string) { return j.failReason, j.failDetail }
+
+func (j *fakeJob) BufferOps(ctx context.Context, since *string, rev string, ops []*bufferedOp) (bool, error) {
+	return false, nil
+}
+
+func (j *fakeJob) FlushBufferedOps(ctx context.Context, cb func(kind, rev, path string, rec *[]byte, cid *cid.Cid) error) error {
+	return nil
+}
+
+func (j *fakeJob) ClearBufferedOps(ctx context.Context) error {
+	j.clearedOps = true
+	return nil
+}
+
+// fakeStore is a minimal in-memory Store used to exercise BumpRetry calls.
+type fakeStore struct {
+	bumpCalls    int
+	bumpedReason FailureReason
+	bumpedRepo   string
+}
+
+func (s *fakeStore) GetJob(ctx context.Context, repo string) (Job, error) { return nil, nil }
+func (s *fakeStore) GetNextEnqueuedJob(ctx context.Context) (Job, error)  { return nil, nil }
+func (s *fakeStore) UpdateRev(ctx context.Context, repo, rev string) error {
+	return nil
+}
+func (s *fakeStore) EnqueueJob(ctx context.Context, repo string) error { return nil }
+
+func (s *fakeStore) BumpRetry(ctx context.Context, repo string, reason FailureReason, retryAt time.Time) (int, error) {
+	s.bumpCalls++
+	s.bumpedReason = reason
+	s.bumpedRepo = repo
+	return s.bumpCalls, nil
+}
+
+func discardLog() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestApplyBackfillResultSuccess(t *testing.T) {
+	j := &fakeJob{repo: "did:example:ok", state: StateInProgress}
+	store := &fakeStore{}
+	b := &Backfiller{Name: "test", Store: store}
+
+	b.applyBackfillResult(context.Background(), discardLog(), j, StateComplete, nil)
+
+	if store.bumpCalls != 0 {
+		t.Fatalf("BumpRetry should not be called on success, got %d calls", store.bumpCalls)
+	}
+	if j.clearedOps {
+		t.Fatal("buffered ops should not be cleared on success")
+	}
+	// FlushBuffer already recorded the StateComplete transition; this
+	// SetState call just keeps the store in sync with that.
+	if j.state != StateComplete {
+		t.Fatalf("job state = %q, want %q", j.state, StateComplete)
+	}
+}
+
+func TestApplyBackfillResultRetryableFailureBumpsRetryAndKeepsBufferedOps(t *testing.T) {
+	j := &fakeJob{repo: "did:example:retry", state: StateInProgress, retryCount: 1}
+	j.failReason = FailUpstream5xx
+	store := &fakeStore{}
+	b := &Backfiller{Name: "test", Store: store}
+
+	b.applyBackfillResult(context.Background(), discardLog(), j, "failed (upstream 500)", errors.New("boom"))
+
+	if store.bumpCalls != 1 {
+		t.Fatalf("expected exactly one BumpRetry call, got %d", store.bumpCalls)
+	}
+	if store.bumpedReason != FailUpstream5xx {
+		t.Fatalf("BumpRetry reason = %q, want %q", store.bumpedReason, FailUpstream5xx)
+	}
+	if j.state != StateEnqueued {
+		t.Fatalf("job state = %q, want %q", j.state, StateEnqueued)
+	}
+	if j.clearedOps {
+		t.Fatal("buffered ops must be kept for a retryable failure, not cleared")
+	}
+}
+
+func TestApplyBackfillResultRepoNotFoundIsNeverRetried(t *testing.T) {
+	j := &fakeJob{repo: "did:example:gone", state: StateInProgress}
+	j.failReason = FailRepoNotFound
+	store := &fakeStore{}
+	b := &Backfiller{Name: "test", Store: store}
+
+	b.applyBackfillResult(context.Background(), discardLog(), j, "failed (repo not found)", errors.New("400"))
+
+	if store.bumpCalls != 0 {
+		t.Fatalf("FailRepoNotFound must never be retried, but BumpRetry was called %d times", store.bumpCalls)
+	}
+	if j.state != "failed_permanent (repo not found)" {
+		t.Fatalf("job state = %q, want failed_permanent variant", j.state)
+	}
+	if !j.clearedOps {
+		t.Fatal("buffered ops should be cleared for a terminal failure")
+	}
+}
+
+func TestApplyBackfillResultRetriesExhausted(t *testing.T) {
+	j := &fakeJob{repo: "did:example:exhausted", state: StateInProgress, retryCount: MaxRetries}
+	j.failReason = FailUpstream5xx
+	store := &fakeStore{}
+	b := &Backfiller{Name: "test", Store: store}
+
+	b.applyBackfillResult(context.Background(), discardLog(), j, "failed (upstream 500)", errors.New("boom"))
+
+	if store.bumpCalls != 0 {
+		t.Fatalf("exhausted retries must not bump again, but BumpRetry was called %d times", store.bumpCalls)
+	}
+	if j.state != "failed_permanent (upstream 500)" {
+		t.Fatalf("job state = %q, want failed_permanent variant", j.state)
+	}
+	if !j.clearedOps {
+		t.Fatal("buffered ops should be cleared once retries are exhausted")
+	}
+}
+
+func TestRecoverBackfillAttemptRecoversPanicAndSchedulesRetry(t *testing.T) {
+	j := &fakeJob{repo: "did:example:panics", state: StateInProgress}
+
+	before := testutil.ToFloat64(backfillPanics.WithLabelValues("backfill_repo"))
+
+	newState, err := recoverBackfillAttempt(context.Background(), discardLog(), j, func() (string, error) {
+		panic("simulated backfill panic")
+	})
+
+	if err == nil {
+		t.Fatal("expected an error describing the recovered panic")
+	}
+	if newState == "" || newState == StateComplete {
+		t.Fatalf("expected a failed state after a panic, got %q", newState)
+	}
+	if j.failReason != FailUnknown {
+		t.Fatalf("job failure reason = %q, want %q", j.failReason, FailUnknown)
+	}
+
+	after := testutil.ToFloat64(backfillPanics.WithLabelValues("backfill_repo"))
+	if after != before+1 {
+		t.Fatalf("backfillPanics{label=backfill_repo} = %v, want %v", after, before+1)
+	}
+
+	// The resulting failed state must still be picked up by the normal
+	// retry path so the job isn't abandoned in StateInProgress forever.
+	store := &fakeStore{}
+	b := &Backfiller{Name: "test", Store: store}
+	b.applyBackfillResult(context.Background(), discardLog(), j, newState, err)
+	if store.bumpCalls != 1 {
+		t.Fatalf("expected the panic-derived failure to be retried, got %d BumpRetry calls", store.bumpCalls)
+	}
+}
+
+func TestRunSafelyRecoversPanicAndReleasesWaitGroup(t *testing.T) {
+	before := testutil.ToFloat64(backfillPanics.WithLabelValues("test_consumer_panic"))
+
+	// Mirrors BackfillRepo's consumers: the WaitGroup slot is released by a
+	// defer outside runSafely, which must still run after the panic.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runSafely(discardLog(), "test_consumer_panic", "did:example:consumer", func() {
+			panic("simulated consumer panic")
+		})
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("WaitGroup slot was not released after runSafely recovered a panic")
+	}
+
+	after := testutil.ToFloat64(backfillPanics.WithLabelValues("test_consumer_panic"))
+	if after != before+1 {
+		t.Fatalf("backfillPanics{label=test_consumer_panic} = %v, want %v", after, before+1)
+	}
+}
@@ -0,0 +1,69 @@
+package backfill
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestEmitDropsWhenSubscriberBufferFull(t *testing.T) {
+	b := &Backfiller{Name: "test"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := b.Subscribe(ctx)
+
+	const sent = subscriberBufferSize + 5
+	for i := 0; i < sent; i++ {
+		b.emit(BackfillEvent{Repo: "did:example:alice"})
+	}
+
+	if got := len(ch); got != subscriberBufferSize {
+		t.Fatalf("subscriber channel has %d buffered events, want %d (excess should have been dropped)", got, subscriberBufferSize)
+	}
+}
+
+func TestSubscribeClosesChannelOnContextDone(t *testing.T) {
+	b := &Backfiller{Name: "test"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := b.Subscribe(ctx)
+
+	cancel()
+
+	// Draining a closed channel with no pending sends should yield the zero
+	// value and ok == false.
+	for range ch {
+	}
+	if _, ok := <-ch; ok {
+		t.Fatal("expected channel to be closed after ctx was cancelled")
+	}
+}
+
+// TestEmitCancelSubscribeRaceDoesNotPanic guards against emit sending on (or
+// racing) a channel that Subscribe's cleanup goroutine is concurrently
+// closing - run with -race to actually catch a regression here.
+func TestEmitCancelSubscribeRaceDoesNotPanic(t *testing.T) {
+	b := &Backfiller{Name: "test"}
+
+	for i := 0; i < 200; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := b.Subscribe(ctx)
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			cancel()
+		}()
+		go func() {
+			defer wg.Done()
+			b.emit(BackfillEvent{Repo: "did:example:race"})
+		}()
+		wg.Wait()
+
+		for range ch {
+		}
+	}
+}